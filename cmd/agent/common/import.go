@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// Package common holds the blank imports that wire builtin checks into the
+// agent binary. Each check registers itself with the collector from its own
+// init(), so nothing here is called directly - the imports just need to run.
+package common
+
+import (
+	// core checks
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/hostmetrics"
+)
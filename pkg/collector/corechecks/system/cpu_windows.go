@@ -13,7 +13,9 @@ package system
 
 import (
 	"fmt"
+	"runtime"
 	"strconv"
+	"strings"
 	"unsafe"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -22,6 +24,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/winutil/pdhutil"
 	"github.com/DataDog/gohai/cpu"
+	yaml "gopkg.in/yaml.v2"
+
 	"golang.org/x/sys/windows"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
@@ -29,12 +33,32 @@ import (
 
 var (
 	modkernel32 = windows.NewLazyDLL("kernel32.dll")
+	modntdll    = windows.NewLazySystemDLL("ntdll.dll")
 
-	procGetSystemTimes = modkernel32.NewProc("GetSystemTimes")
+	procGetSystemTimes           = modkernel32.NewProc("GetSystemTimes")
+	procNtQuerySystemInformation = modntdll.NewProc("NtQuerySystemInformation")
 )
 
 const cpuCheckName = "cpu"
 
+// systemProcessorPerformanceInformationClass is the SYSTEM_INFORMATION_CLASS
+// value (class 8) that makes NtQuerySystemInformation return one
+// SYSTEM_PROCESSOR_PERFORMANCE_INFORMATION struct per logical processor.
+const systemProcessorPerformanceInformationClass = 8
+
+// systemProcessorPerformanceInformation mirrors the Windows
+// SYSTEM_PROCESSOR_PERFORMANCE_INFORMATION struct. All time fields are
+// LARGE_INTEGERs expressed in 100ns units.
+type systemProcessorPerformanceInformation struct {
+	IdleTime       int64
+	KernelTime     int64
+	UserTime       int64
+	DpcTime        int64
+	InterruptTime  int64
+	InterruptCount uint32
+	_              uint32 // padding, the struct is naturally aligned to 8 bytes
+}
+
 // For testing purpose
 var times = Times
 
@@ -42,19 +66,28 @@ var times = Times
 // kinds of work. Time units are in USER_HZ or Jiffies (typically hundredths of
 // a second). It is based on linux /proc/stat file.
 type TimesStat struct {
-	CPU    string
-	User   float64
-	System float64
-	Idle   float64
+	CPU       string
+	User      float64
+	System    float64
+	Idle      float64
+	Interrupt float64
+	Dpc       float64
+}
+
+// cpuInstanceConfig is the instance configuration for the cpu check
+type cpuInstanceConfig struct {
+	ReportPerCPU bool `yaml:"report_per_cpu"`
 }
 
 // CPUCheck doesn't need additional fields
 type CPUCheck struct {
 	core.CheckBase
-	nbCPU       float64
-	lastNbCycle float64
-	lastTimes   TimesStat
-	counter     *pdhutil.PdhCounterSet
+	nbCPU        float64
+	lastNbCycle  float64
+	lastTimes    TimesStat
+	lastPerCPU   map[string]TimesStat
+	reportPerCPU bool
+	counter      *pdhutil.PdhCounterSet
 }
 
 // Total returns the total number of seconds in a CPUTimesStat
@@ -80,6 +113,7 @@ func (c *CPUCheck) Run() error {
 		return errEmpty
 	}
 	t := cpuTimes[0]
+	perCPU := cpuTimes[1:]
 
 	nbCycle := t.Total() / c.nbCPU
 
@@ -100,23 +134,77 @@ func (c *CPUCheck) Run() error {
 		sender.Gauge("system.cpu.idle", idle*toPercent, "", nil)
 		sender.Gauge("system.cpu.stolen", stolen*toPercent, "", nil)
 		sender.Gauge("system.cpu.guest", guest*toPercent, "", nil)
-	}
-	val, err := c.counter.GetSingleValue()
-	if err != nil {
-		log.Warnf("Error getting handle value %v", err)
-	} else {
-		sender.Gauge("system.cpu.interrupt", float64(val), "", nil)
+
+		if t.Interrupt != 0 || t.Dpc != 0 {
+			interrupt := (t.Interrupt - c.lastTimes.Interrupt) / c.nbCPU
+			dpc := (t.Dpc - c.lastTimes.Dpc) / c.nbCPU
+			sender.Gauge("system.cpu.interrupt", interrupt*toPercent, "", nil)
+			sender.Gauge("system.cpu.dpc", dpc*toPercent, "", nil)
+		} else if val, err := c.counter.GetSingleValue(); err != nil {
+			log.Warnf("Error getting handle value %v", err)
+		} else {
+			sender.Gauge("system.cpu.interrupt", float64(val), "", nil)
+		}
+
+		if c.reportPerCPU {
+			c.reportPerCPUMetrics(sender, perCPU, toPercent)
+		}
 	}
 	sender.Commit()
 
 	c.lastNbCycle = nbCycle
 	c.lastTimes = t
+	c.lastPerCPU = toPerCPUMap(perCPU)
 	return nil
 }
 
-// Configure the CPU check doesn't need configuration
+// reportPerCPUMetrics emits system.cpu.user/system/idle for each logical
+// processor, tagged with cpu:N, using the same delta-over-cycles math as the
+// aggregate metrics above.
+func (c *CPUCheck) reportPerCPUMetrics(sender aggregator.Sender, perCPU []TimesStat, toPercent float64) {
+	for _, cur := range perCPU {
+		last, ok := c.lastPerCPU[cur.CPU]
+		if !ok {
+			continue
+		}
+		tags := []string{"cpu:" + perCPUIndex(cur.CPU)}
+		user, system, idle := perCPUDelta(cur, last, toPercent)
+		sender.Gauge("system.cpu.user", user, "", tags)
+		sender.Gauge("system.cpu.system", system, "", tags)
+		sender.Gauge("system.cpu.idle", idle, "", tags)
+	}
+}
+
+// perCPUIndex turns a TimesStat.CPU label such as "cpu0" into the bare index
+// ("0") used for the cpu:N tag.
+func perCPUIndex(cpuLabel string) string {
+	return strings.TrimPrefix(cpuLabel, "cpu")
+}
+
+// perCPUDelta computes the instantaneous user/system/idle percentages for one
+// logical processor from two samples of Times().
+func perCPUDelta(cur, last TimesStat, toPercent float64) (user, system, idle float64) {
+	return (cur.User - last.User) * toPercent,
+		(cur.System - last.System) * toPercent,
+		(cur.Idle - last.Idle) * toPercent
+}
+
+func toPerCPUMap(perCPU []TimesStat) map[string]TimesStat {
+	m := make(map[string]TimesStat, len(perCPU))
+	for _, t := range perCPU {
+		m[t.CPU] = t
+	}
+	return m
+}
+
+// Configure the CPU check
 func (c *CPUCheck) Configure(data integration.Data, initConfig integration.Data) error {
-	// do nothing
+	conf := cpuInstanceConfig{}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return err
+	}
+	c.reportPerCPU = conf.ReportPerCPU
+
 	info, err := cpu.GetCpuInfo()
 	if err != nil {
 		return fmt.Errorf("system.CPUCheck: could not query CPU info")
@@ -147,8 +235,71 @@ type FILETIME struct {
 	DwHighDateTime uint32
 }
 
-// Times returns times stat per cpu and combined for all CPUs
+// Times returns times stat per cpu and combined for all CPUs. The first
+// element of the returned slice is always the "cpu-total" aggregate; when
+// NtQuerySystemInformation succeeds, one entry per logical processor follows.
+// If the syscall is unavailable (e.g. on older Windows builds), it falls back
+// to GetSystemTimes and only the aggregate is returned, as before.
 func Times() ([]TimesStat, error) {
+	perCPU, err := PerCPUTimes()
+	if err != nil {
+		log.Debugf("system.CPUCheck: NtQuerySystemInformation unavailable (%s), falling back to GetSystemTimes", err)
+		return legacyTimes()
+	}
+
+	total := TimesStat{CPU: "cpu-total"}
+	for _, t := range perCPU {
+		total.Idle += t.Idle
+		total.User += t.User
+		total.System += t.System
+		total.Interrupt += t.Interrupt
+		total.Dpc += t.Dpc
+	}
+
+	ret := make([]TimesStat, 0, len(perCPU)+1)
+	ret = append(ret, total)
+	ret = append(ret, perCPU...)
+	return ret, nil
+}
+
+// PerCPUTimes returns one TimesStat per logical processor, computed from
+// NtQuerySystemInformation(SystemProcessorPerformanceInformation). It does
+// not include the "cpu-total" aggregate; use Times for that.
+func PerCPUTimes() ([]TimesStat, error) {
+	ncpu := runtime.NumCPU()
+	buf := make([]systemProcessorPerformanceInformation, ncpu)
+	size := uintptr(ncpu) * unsafe.Sizeof(buf[0])
+	var retLen uint32
+
+	r, _, _ := procNtQuerySystemInformation.Call(
+		uintptr(systemProcessorPerformanceInformationClass),
+		uintptr(unsafe.Pointer(&buf[0])),
+		size,
+		uintptr(unsafe.Pointer(&retLen)),
+	)
+	if r != 0 {
+		return nil, fmt.Errorf("NtQuerySystemInformation failed with NTSTATUS 0x%x", r)
+	}
+
+	ret := make([]TimesStat, 0, ncpu)
+	for i, info := range buf {
+		idle := float64(info.IdleTime)
+		kernel := float64(info.KernelTime)
+		ret = append(ret, TimesStat{
+			CPU:       "cpu" + strconv.Itoa(i),
+			Idle:      idle,
+			User:      float64(info.UserTime),
+			System:    kernel - idle,
+			Interrupt: float64(info.InterruptTime),
+			Dpc:       float64(info.DpcTime),
+		})
+	}
+	return ret, nil
+}
+
+// legacyTimes is the original GetSystemTimes-based implementation, kept as a
+// fallback for systems where NtQuerySystemInformation is unavailable.
+func legacyTimes() ([]TimesStat, error) {
 	var ret []TimesStat
 	var lpIdleTime FILETIME
 	var lpKernelTime FILETIME
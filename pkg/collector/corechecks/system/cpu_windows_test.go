@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build windows
+
+package system
+
+import "testing"
+
+func TestPerCPUIndex(t *testing.T) {
+	for _, tc := range []struct {
+		label string
+		want  string
+	}{
+		{"cpu0", "0"},
+		{"cpu1", "1"},
+		{"cpu12", "12"},
+	} {
+		if got := perCPUIndex(tc.label); got != tc.want {
+			t.Errorf("perCPUIndex(%q) = %q, want %q", tc.label, got, tc.want)
+		}
+	}
+}
+
+func TestPerCPUDelta(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		cur, last  TimesStat
+		toPercent  float64
+		wantUser   float64
+		wantSystem float64
+		wantIdle   float64
+	}{
+		{
+			name:       "no change",
+			cur:        TimesStat{User: 10, System: 5, Idle: 85},
+			last:       TimesStat{User: 10, System: 5, Idle: 85},
+			toPercent:  1,
+			wantUser:   0,
+			wantSystem: 0,
+			wantIdle:   0,
+		},
+		{
+			name:       "all idle",
+			cur:        TimesStat{User: 10, System: 5, Idle: 185},
+			last:       TimesStat{User: 10, System: 5, Idle: 85},
+			toPercent:  1,
+			wantUser:   0,
+			wantSystem: 0,
+			wantIdle:   100,
+		},
+		{
+			name:       "toPercent scaling",
+			cur:        TimesStat{User: 20, System: 10, Idle: 85},
+			last:       TimesStat{User: 10, System: 5, Idle: 85},
+			toPercent:  2,
+			wantUser:   20,
+			wantSystem: 10,
+			wantIdle:   0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			user, system, idle := perCPUDelta(tc.cur, tc.last, tc.toPercent)
+			if user != tc.wantUser || system != tc.wantSystem || idle != tc.wantIdle {
+				t.Errorf("perCPUDelta() = (%v, %v, %v), want (%v, %v, %v)",
+					user, system, idle, tc.wantUser, tc.wantSystem, tc.wantIdle)
+			}
+		})
+	}
+}
+
+func TestToPerCPUMap(t *testing.T) {
+	perCPU := []TimesStat{
+		{CPU: "cpu0", User: 1},
+		{CPU: "cpu1", User: 2},
+	}
+
+	m := toPerCPUMap(perCPU)
+	if len(m) != 2 {
+		t.Fatalf("len(toPerCPUMap(...)) = %d, want 2", len(m))
+	}
+	if m["cpu0"].User != 1 || m["cpu1"].User != 2 {
+		t.Errorf("toPerCPUMap(...) = %+v, want cpu0.User=1 cpu1.User=2", m)
+	}
+}
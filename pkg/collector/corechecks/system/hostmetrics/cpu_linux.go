@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build linux
+
+package hostmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+)
+
+const cpuCheckName = "host_metrics_cpu"
+
+// cpuTimes holds the per-CPU jiffie counters read from one "cpuN" line of
+// /proc/stat.
+type cpuTimes struct {
+	cpu    string
+	user   float64
+	system float64
+	idle   float64
+}
+
+func (t cpuTimes) total() float64 {
+	return t.user + t.system + t.idle
+}
+
+// cpuCheck emits system.host.cpu.* per logical processor, reading
+// /proc/stat directly rather than depending on the Windows-only Times().
+type cpuCheck struct {
+	core.CheckBase
+	last map[string]cpuTimes
+}
+
+// Run executes the check
+func (c *cpuCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	times, err := readProcStat()
+	if err != nil {
+		return err
+	}
+
+	cur := make(map[string]cpuTimes, len(times))
+	for _, t := range times {
+		cur[t.cpu] = t
+
+		last, ok := c.last[t.cpu]
+		if !ok {
+			continue
+		}
+		delta := t.total() - last.total()
+		if delta <= 0 {
+			continue
+		}
+
+		tags := []string{"cpu:" + strings.TrimPrefix(t.cpu, "cpu")}
+		sender.Gauge("system.host.cpu.user", 100*(t.user-last.user)/delta, "", tags)
+		sender.Gauge("system.host.cpu.system", 100*(t.system-last.system)/delta, "", tags)
+		sender.Gauge("system.host.cpu.idle", 100*(t.idle-last.idle)/delta, "", tags)
+	}
+	sender.Commit()
+
+	c.last = cur
+	return nil
+}
+
+// readProcStat returns one cpuTimes per logical processor, skipping the
+// aggregate "cpu" line.
+func readProcStat() ([]cpuTimes, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var times []cpuTimes
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		t, ok := parseProcStatLine(scanner.Text())
+		if ok {
+			times = append(times, t)
+		}
+	}
+	return times, scanner.Err()
+}
+
+// parseProcStatLine parses one "cpuN user nice system idle iowait irq
+// softirq ..." line (in USER_HZ) into a cpuTimes, folding nice into user,
+// iowait into idle, and irq and softirq into system. It returns ok=false for
+// the aggregate "cpu" line or any non-cpu line.
+func parseProcStatLine(line string) (t cpuTimes, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] == "cpu" || !strings.HasPrefix(fields[0], "cpu") {
+		return cpuTimes{}, false
+	}
+
+	values := make([]float64, len(fields)-1)
+	for i, f := range fields[1:] {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return cpuTimes{}, false
+		}
+		values[i] = v
+	}
+
+	idle := values[3]
+	if len(values) > 4 {
+		idle += values[4] // iowait
+	}
+	system := values[2]
+	if len(values) > 6 {
+		system += values[5] + values[6] // irq, softirq
+	}
+
+	return cpuTimes{
+		cpu:    fields[0],
+		user:   values[0] + values[1], // user + nice
+		system: system,
+		idle:   idle,
+	}, true
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *cpuCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newCPUCheck() check.Check {
+	return &cpuCheck{CheckBase: core.NewCheckBase(cpuCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(cpuCheckName, newCPUCheck)
+}
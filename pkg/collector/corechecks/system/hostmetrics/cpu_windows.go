@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build windows
+
+package hostmetrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	system "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const cpuCheckName = "host_metrics_cpu"
+
+// cpuCheck emits system.host.cpu.* per logical processor, reusing the
+// per-CPU Times() path added for the regular cpu check.
+type cpuCheck struct {
+	core.CheckBase
+	last map[string]system.TimesStat
+}
+
+// Run executes the check
+func (c *cpuCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	times, err := system.Times()
+	if err != nil {
+		log.Errorf("hostmetrics: could not retrieve cpu stats: %s", err)
+		return err
+	}
+
+	cur := make(map[string]system.TimesStat, len(times))
+	for _, t := range times[1:] {
+		cur[t.CPU] = t
+
+		last, ok := c.last[t.CPU]
+		if !ok {
+			continue
+		}
+		delta := t.Total() - last.Total()
+		if delta <= 0 {
+			continue
+		}
+
+		tags := []string{"cpu:" + strings.TrimPrefix(t.CPU, "cpu")}
+		sender.Gauge("system.host.cpu.user", 100*(t.User-last.User)/delta, "", tags)
+		sender.Gauge("system.host.cpu.system", 100*(t.System-last.System)/delta, "", tags)
+		sender.Gauge("system.host.cpu.idle", 100*(t.Idle-last.Idle)/delta, "", tags)
+	}
+	sender.Commit()
+
+	c.last = cur
+	return nil
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *cpuCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newCPUCheck() check.Check {
+	return &cpuCheck{CheckBase: core.NewCheckBase(cpuCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(cpuCheckName, newCPUCheck)
+}
@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build linux
+
+package hostmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const diskCheckName = "host_metrics_disk"
+
+// pseudoFsTypes lists filesystems that don't represent real storage and
+// shouldn't be reported on.
+var pseudoFsTypes = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "tmpfs": true,
+	"devpts": true, "cgroup": true, "cgroup2": true, "overlay": true,
+	"squashfs": true, "debugfs": true, "mqueue": true, "tracefs": true,
+}
+
+type diskCheck struct {
+	core.CheckBase
+}
+
+// Run executes the check
+func (c *diskCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	mounts, err := readMounts()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounts {
+		if pseudoFsTypes[m.fstype] {
+			continue
+		}
+
+		var stat unix.Statfs_t
+		if err := unix.Statfs(m.path, &stat); err != nil {
+			log.Debugf("hostmetrics: could not stat %s: %s", m.path, err)
+			continue
+		}
+
+		bsize := uint64(stat.Bsize)
+		total := stat.Blocks * bsize
+		free := stat.Bfree * bsize
+		tags := []string{"device:" + m.device, "fstype:" + m.fstype}
+		sender.Gauge("system.host.disk.total", float64(total), "", tags)
+		sender.Gauge("system.host.disk.used", float64(total-free), "", tags)
+		sender.Gauge("system.host.disk.free", float64(free), "", tags)
+	}
+	sender.Commit()
+	return nil
+}
+
+type mountInfo struct {
+	device string
+	path   string
+	fstype string
+}
+
+func readMounts() ([]mountInfo, error) {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseMounts(f)
+}
+
+// parseMounts parses the "device path fstype ..." lines of /proc/mounts.
+func parseMounts(r io.Reader) ([]mountInfo, error) {
+	var mounts []mountInfo
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, mountInfo{device: fields[0], path: fields[1], fstype: fields[2]})
+	}
+	return mounts, scanner.Err()
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *diskCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newDiskCheck() check.Check {
+	return &diskCheck{CheckBase: core.NewCheckBase(diskCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(diskCheckName, newDiskCheck)
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build windows
+
+package hostmetrics
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/winutil"
+)
+
+const diskCheckName = "host_metrics_disk"
+
+var (
+	modkernel32Disk            = windows.NewLazyDLL("kernel32.dll")
+	procGetLogicalDriveStrings = modkernel32Disk.NewProc("GetLogicalDriveStringsW")
+	procGetDiskFreeSpaceEx     = modkernel32Disk.NewProc("GetDiskFreeSpaceExW")
+)
+
+type diskCheck struct {
+	core.CheckBase
+}
+
+// Run executes the check
+func (c *diskCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	drives, err := logicalDrives()
+	if err != nil {
+		return err
+	}
+
+	for _, drive := range drives {
+		driveUTF16, err := windows.UTF16PtrFromString(drive)
+		if err != nil {
+			continue
+		}
+
+		var freeBytes, totalBytes, totalFreeBytes uint64
+		r, _, callErr := procGetDiskFreeSpaceEx.Call(
+			uintptr(unsafe.Pointer(driveUTF16)),
+			uintptr(unsafe.Pointer(&freeBytes)),
+			uintptr(unsafe.Pointer(&totalBytes)),
+			uintptr(unsafe.Pointer(&totalFreeBytes)),
+		)
+		if r == 0 {
+			log.Warnf("hostmetrics: could not query free space for %s: %s", drive, callErr)
+			continue
+		}
+
+		tags := []string{"device:" + drive, "fstype:" + winutil.GetDriveFsType(drive)}
+		sender.Gauge("system.host.disk.total", float64(totalBytes), "", tags)
+		sender.Gauge("system.host.disk.used", float64(totalBytes-totalFreeBytes), "", tags)
+		sender.Gauge("system.host.disk.free", float64(totalFreeBytes), "", tags)
+	}
+	sender.Commit()
+	return nil
+}
+
+// logicalDrives returns the drive roots (ex: "C:\\") reported by
+// GetLogicalDriveStrings
+func logicalDrives() ([]string, error) {
+	buf := make([]uint16, 254)
+	r, _, err := procGetLogicalDriveStrings.Call(uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+	if r == 0 {
+		return nil, err
+	}
+
+	var drives []string
+	for _, s := range strings.Split(windows.UTF16ToString(buf), "\x00") {
+		if s != "" {
+			drives = append(drives, s)
+		}
+	}
+	return drives, nil
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *diskCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newDiskCheck() check.Check {
+	return &diskCheck{CheckBase: core.NewCheckBase(diskCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(diskCheckName, newDiskCheck)
+}
@@ -0,0 +1,28 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// Package hostmetrics bundles the CPU, memory and disk checks that make up
+// the "host metrics" subsystem. Like the regular corechecks, each check
+// self-registers with the collector from its own init(); unlike them, none
+// takes instance config - every check's Configure instead calls enabled(),
+// which reads the telemetry.enable_host_metrics setting (or, if it's unset,
+// auto-detects a managed/cloud environment) and makes the check fail to
+// schedule when host metrics collection isn't wanted.
+//
+// The subsystem reports the same metric names on every platform:
+//
+//	system.host.cpu.user      gauge, percent, tags: host, cpu
+//	system.host.cpu.system    gauge, percent, tags: host, cpu
+//	system.host.cpu.idle      gauge, percent, tags: host, cpu
+//	system.host.mem.total     gauge, bytes,   tags: host
+//	system.host.mem.used      gauge, bytes,   tags: host
+//	system.host.mem.free      gauge, bytes,   tags: host
+//	system.host.disk.total    gauge, bytes,   tags: host, device, fstype
+//	system.host.disk.used     gauge, bytes,   tags: host, device, fstype
+//	system.host.disk.free     gauge, bytes,   tags: host, device, fstype
+//
+// Operators relying on these metrics should be able to build one dashboard
+// that works regardless of which OS the agent runs on.
+package hostmetrics
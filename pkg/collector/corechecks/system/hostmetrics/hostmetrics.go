@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+package hostmetrics
+
+import (
+	"os"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// managedEnvVars are environment variables set by common cloud/container
+// platforms that indicate the agent is running under a managed integration,
+// where host metrics are cheap to collect and generally expected.
+var managedEnvVars = []string{
+	"KUBERNETES_SERVICE_HOST",
+	"ECS_CONTAINER_METADATA_URI",
+	"ECS_CONTAINER_METADATA_URI_V4",
+	"AWS_EXECUTION_ENV",
+}
+
+// enabled reports whether the host metrics subsystem should collect, per the
+// telemetry.enable_host_metrics config key (auto-true under a managed/cloud
+// integration when the key isn't set). Each sub-check calls this from its
+// own Configure, the same way any other corecheck validates its config.
+func enabled() bool {
+	if config.Datadog.IsSet("telemetry.enable_host_metrics") {
+		return config.Datadog.GetBool("telemetry.enable_host_metrics")
+	}
+	return isManagedEnvironment()
+}
+
+func isManagedEnvironment() bool {
+	for _, envVar := range managedEnvVars {
+		if _, ok := os.LookupEnv(envVar); ok {
+			return true
+		}
+	}
+	return false
+}
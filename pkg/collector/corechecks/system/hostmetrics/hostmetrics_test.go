@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+package hostmetrics
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsManagedEnvironment(t *testing.T) {
+	for _, v := range managedEnvVars {
+		old, had := os.LookupEnv(v)
+		os.Unsetenv(v)
+		if had {
+			defer os.Setenv(v, old)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		envVar string
+		want   bool
+	}{
+		{"no env vars set", "", false},
+		{"kubernetes", "KUBERNETES_SERVICE_HOST", true},
+		{"ecs", "ECS_CONTAINER_METADATA_URI", true},
+		{"ecs v4", "ECS_CONTAINER_METADATA_URI_V4", true},
+		{"lambda-style", "AWS_EXECUTION_ENV", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.envVar != "" {
+				os.Setenv(tc.envVar, "1")
+				defer os.Unsetenv(tc.envVar)
+			}
+			if got := isManagedEnvironment(); got != tc.want {
+				t.Errorf("isManagedEnvironment() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
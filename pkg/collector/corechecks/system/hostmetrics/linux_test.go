@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build linux
+
+package hostmetrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProcStatLine(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		line string
+		want cpuTimes
+		ok   bool
+	}{
+		{
+			name: "aggregate line is skipped",
+			line: "cpu  100 0 200 700 0 0 0 0 0 0",
+			ok:   false,
+		},
+		{
+			name: "per-cpu line",
+			line: "cpu0 10 5 20 70 3 1 1 0 0 0",
+			want: cpuTimes{cpu: "cpu0", user: 15, system: 22, idle: 73},
+			ok:   true,
+		},
+		{
+			name: "non-cpu line is skipped",
+			line: "intr 12345 0 0",
+			ok:   false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseProcStatLine(tc.line)
+			if ok != tc.ok {
+				t.Fatalf("parseProcStatLine(%q) ok = %v, want %v", tc.line, ok, tc.ok)
+			}
+			if ok && got != tc.want {
+				t.Errorf("parseProcStatLine(%q) = %+v, want %+v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	const meminfo = `MemTotal:       16384000 kB
+MemFree:         2048000 kB
+MemAvailable:    8192000 kB
+Buffers:          512000 kB
+`
+	total, free, err := parseMeminfo(strings.NewReader(meminfo))
+	if err != nil {
+		t.Fatalf("parseMeminfo() error = %v", err)
+	}
+	if want := uint64(16384000 * 1024); total != want {
+		t.Errorf("total = %d, want %d", total, want)
+	}
+	if want := uint64(8192000 * 1024); free != want {
+		t.Errorf("free = %d, want %d", free, want)
+	}
+}
+
+func TestParseMounts(t *testing.T) {
+	const mounts = `/dev/sda1 / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid 0 0
+tmpfs /dev/shm tmpfs rw 0 0
+`
+	got, err := parseMounts(strings.NewReader(mounts))
+	if err != nil {
+		t.Fatalf("parseMounts() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len(parseMounts(...)) = %d, want 3", len(got))
+	}
+	if got[0] != (mountInfo{device: "/dev/sda1", path: "/", fstype: "ext4"}) {
+		t.Errorf("got[0] = %+v, want root ext4 mount", got[0])
+	}
+}
+
+func TestPseudoFsTypesFiltersRealMounts(t *testing.T) {
+	for _, tc := range []struct {
+		fstype string
+		skip   bool
+	}{
+		{"ext4", false},
+		{"xfs", false},
+		{"proc", true},
+		{"tmpfs", true},
+		{"overlay", true},
+	} {
+		if got := pseudoFsTypes[tc.fstype]; got != tc.skip {
+			t.Errorf("pseudoFsTypes[%q] = %v, want %v", tc.fstype, got, tc.skip)
+		}
+	}
+}
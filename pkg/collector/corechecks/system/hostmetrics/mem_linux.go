@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build linux
+
+package hostmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+)
+
+const memCheckName = "host_metrics_mem"
+
+type memCheck struct {
+	core.CheckBase
+}
+
+// Run executes the check
+func (c *memCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	total, free, err := readMeminfo()
+	if err != nil {
+		return err
+	}
+
+	sender.Gauge("system.host.mem.total", float64(total), "", nil)
+	sender.Gauge("system.host.mem.used", float64(total-free), "", nil)
+	sender.Gauge("system.host.mem.free", float64(free), "", nil)
+	sender.Commit()
+	return nil
+}
+
+// readMeminfo returns MemTotal and MemAvailable, in bytes, from /proc/meminfo
+func readMeminfo() (total uint64, free uint64, err error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	return parseMeminfo(f)
+}
+
+// parseMeminfo extracts MemTotal and MemAvailable, in bytes, from the
+// "key: value kB" lines of /proc/meminfo.
+func parseMeminfo(r io.Reader) (total uint64, free uint64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, convErr := strconv.ParseUint(fields[1], 10, 64)
+		if convErr != nil {
+			continue
+		}
+		switch strings.TrimSuffix(fields[0], ":") {
+		case "MemTotal":
+			total = kb * 1024
+		case "MemAvailable":
+			free = kb * 1024
+		}
+	}
+	return total, free, scanner.Err()
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *memCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newMemCheck() check.Check {
+	return &memCheck{CheckBase: core.NewCheckBase(memCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(memCheckName, newMemCheck)
+}
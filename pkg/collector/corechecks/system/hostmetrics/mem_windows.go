@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build windows
+
+package hostmetrics
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+)
+
+const memCheckName = "host_metrics_mem"
+
+var (
+	modkernel32Mem           = windows.NewLazyDLL("kernel32.dll")
+	procGlobalMemoryStatusEx = modkernel32Mem.NewProc("GlobalMemoryStatusEx")
+)
+
+// memoryStatusEx mirrors the Windows MEMORYSTATUSEX structure.
+type memoryStatusEx struct {
+	dwLength                uint32
+	dwMemoryLoad            uint32
+	ullTotalPhys            uint64
+	ullAvailPhys            uint64
+	ullTotalPageFile        uint64
+	ullAvailPageFile        uint64
+	ullTotalVirtual         uint64
+	ullAvailVirtual         uint64
+	ullAvailExtendedVirtual uint64
+}
+
+type memCheck struct {
+	core.CheckBase
+}
+
+// Run executes the check
+func (c *memCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	var stat memoryStatusEx
+	stat.dwLength = uint32(unsafe.Sizeof(stat))
+	r, _, callErr := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&stat)))
+	if r == 0 {
+		return callErr
+	}
+
+	sender.Gauge("system.host.mem.total", float64(stat.ullTotalPhys), "", nil)
+	sender.Gauge("system.host.mem.used", float64(stat.ullTotalPhys-stat.ullAvailPhys), "", nil)
+	sender.Gauge("system.host.mem.free", float64(stat.ullAvailPhys), "", nil)
+	sender.Commit()
+	return nil
+}
+
+// Configure checks that the host metrics subsystem is enabled
+func (c *memCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	if !enabled() {
+		return fmt.Errorf("host metrics subsystem is disabled (telemetry.enable_host_metrics)")
+	}
+	return nil
+}
+
+func newMemCheck() check.Check {
+	return &memCheck{CheckBase: core.NewCheckBase(memCheckName)}
+}
+
+func init() {
+	core.RegisterCheck(memCheckName, newMemCheck)
+}
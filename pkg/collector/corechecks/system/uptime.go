@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+package system
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/util/system"
+)
+
+const uptimeCheckName = "uptime"
+
+// UptimeCheck reports how long the host has been running
+type UptimeCheck struct {
+	core.CheckBase
+}
+
+// Run executes the check
+func (c *UptimeCheck) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	uptime, err := system.Uptime()
+	if err != nil {
+		return err
+	}
+
+	sender.Gauge("system.uptime", uptime.Seconds(), "", nil)
+	sender.Commit()
+	return nil
+}
+
+// Configure the uptime check doesn't need configuration
+func (c *UptimeCheck) Configure(data integration.Data, initConfig integration.Data) error {
+	return nil
+}
+
+func uptimeFactory() check.Check {
+	return &UptimeCheck{
+		CheckBase: core.NewCheckBase(uptimeCheckName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(uptimeCheckName, uptimeFactory)
+}
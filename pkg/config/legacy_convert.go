@@ -0,0 +1,218 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// AgentConfig is a (partial) representation of the modern datadog.yaml
+// configuration: only the sections ConvertLegacyAgentConfig knows how to
+// populate from a legacy datadog.conf are modeled here.
+type AgentConfig struct {
+	DDURL    string   `yaml:"dd_url,omitempty"`
+	APIKey   string   `yaml:"api_key,omitempty"`
+	Hostname string   `yaml:"hostname,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+
+	Proxy         *ProxyConfig         `yaml:"proxy,omitempty"`
+	Dogstatsd     *DogstatsdConfig     `yaml:"dogstatsd,omitempty"`
+	APMConfig     *APMConfig           `yaml:"apm_config,omitempty"`
+	Autodiscovery *AutodiscoveryConfig `yaml:"autodiscovery,omitempty"`
+
+	// Warnings lists deprecated datadog.conf keys that were recognised but
+	// dropped during conversion. It is not part of datadog.yaml.
+	Warnings []string `yaml:"-"`
+}
+
+// ProxyConfig is the `proxy` section of datadog.yaml
+type ProxyConfig struct {
+	HTTP  string `yaml:"http,omitempty"`
+	HTTPS string `yaml:"https,omitempty"`
+}
+
+// DogstatsdConfig is the `dogstatsd` section of datadog.yaml
+type DogstatsdConfig struct {
+	HistogramAggregates  []string `yaml:"histogram_aggregates,omitempty"`
+	HistogramPercentiles []string `yaml:"histogram_percentiles,omitempty"`
+}
+
+// APMConfig is the `apm_config` section of datadog.yaml
+type APMConfig struct {
+	ReceiverPort       int      `yaml:"receiver_port,omitempty"`
+	Env                string   `yaml:"env,omitempty"`
+	ExtraSampleRate    float64  `yaml:"extra_sample_rate,omitempty"`
+	MaxTracesPerSecond float64  `yaml:"max_traces_per_second,omitempty"`
+	IgnoreResources    []string `yaml:"ignore_resources,omitempty"`
+}
+
+// AutodiscoveryConfig is the `autodiscovery` section of datadog.yaml
+type AutodiscoveryConfig struct {
+	Backend       string `yaml:"service_discovery_backend,omitempty"`
+	ConfigBackend string `yaml:"sd_config_backend,omitempty"`
+	BackendHost   string `yaml:"sd_backend_host,omitempty"`
+	BackendPort   string `yaml:"sd_backend_port,omitempty"`
+	BackendUser   string `yaml:"sd_backend_username,omitempty"`
+	BackendPass   string `yaml:"sd_backend_password,omitempty"`
+	TemplateDir   string `yaml:"sd_template_dir,omitempty"`
+}
+
+// deprecatedLegacyKeys lists recognised datadog.conf keys that no longer have
+// an equivalent in datadog.yaml and are dropped during conversion.
+var deprecatedLegacyKeys = []string{
+	"proxy_forbid_method_switch",
+	"collect_orchestrator_tags",
+	"use_curl_http_client",
+	"dogstatsd_target",
+	"gce_updated_hostname",
+}
+
+// ConvertLegacyAgentConfig translates a legacy agentConfig map, as returned
+// by GetLegacyAgentConfig, into the modern datadog.yaml structure. Keys with
+// no modern equivalent are recorded in the returned AgentConfig's Warnings
+// field so the caller (e.g. the import CLI command) can surface them.
+func ConvertLegacyAgentConfig(legacy LegacyConfig) (*AgentConfig, error) {
+	conf := &AgentConfig{
+		DDURL:         legacy["dd_url"],
+		APIKey:        legacy["api_key"],
+		Hostname:      legacy["hostname"],
+		Proxy:         convertProxy(legacy),
+		Dogstatsd:     convertDogstatsd(legacy),
+		APMConfig:     convertAPMConfig(legacy),
+		Autodiscovery: convertAutodiscovery(legacy),
+		Warnings:      deprecationWarnings(legacy),
+	}
+
+	if tags := legacy["tags"]; tags != "" {
+		conf.Tags = splitAndTrim(tags)
+	}
+
+	return conf, nil
+}
+
+func convertProxy(legacy LegacyConfig) *ProxyConfig {
+	host := legacy["proxy_host"]
+	if host == "" {
+		return nil
+	}
+
+	if port := legacy["proxy_port"]; port != "" {
+		host = host + ":" + port
+	}
+
+	u := &url.URL{Scheme: "http", Host: host}
+	if user := legacy["proxy_user"]; user != "" {
+		if pass := legacy["proxy_password"]; pass != "" {
+			u.User = url.UserPassword(user, pass)
+		} else {
+			u.User = url.User(user)
+		}
+	}
+
+	proxyURL := u.String()
+	return &ProxyConfig{HTTP: proxyURL, HTTPS: proxyURL}
+}
+
+func convertDogstatsd(legacy LegacyConfig) *DogstatsdConfig {
+	agg := legacy["histogram_aggregates"]
+	pct := legacy["histogram_percentiles"]
+	if agg == "" && pct == "" {
+		return nil
+	}
+
+	conf := &DogstatsdConfig{}
+	if agg != "" {
+		conf.HistogramAggregates = splitAndTrim(agg)
+	}
+	if pct != "" {
+		conf.HistogramPercentiles = splitAndTrim(pct)
+	}
+	return conf
+}
+
+func convertAPMConfig(legacy LegacyConfig) *APMConfig {
+	if legacy["apm_enabled"] != "true" {
+		return nil
+	}
+
+	conf := &APMConfig{Env: legacy["env"]}
+	if port := legacy["receiver_port"]; port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			conf.ReceiverPort = p
+		}
+	}
+	if rate := legacy["extra_sample_rate"]; rate != "" {
+		if f, err := strconv.ParseFloat(rate, 64); err == nil {
+			conf.ExtraSampleRate = f
+		}
+	}
+	if max := legacy["max_traces_per_second"]; max != "" {
+		if f, err := strconv.ParseFloat(max, 64); err == nil {
+			conf.MaxTracesPerSecond = f
+		}
+	}
+	// trace.ignore.resource is imported under the "resource" key, see
+	// supportedValues in legacy_importer.go
+	if res := legacy["resource"]; res != "" {
+		conf.IgnoreResources = splitAndTrim(res)
+	}
+	return conf
+}
+
+func convertAutodiscovery(legacy LegacyConfig) *AutodiscoveryConfig {
+	backend := legacy["service_discovery_backend"]
+	configBackend := legacy["sd_config_backend"]
+	if backend == "" && configBackend == "" {
+		return nil
+	}
+
+	return &AutodiscoveryConfig{
+		Backend:       backend,
+		ConfigBackend: configBackend,
+		BackendHost:   legacy["sd_backend_host"],
+		BackendPort:   legacy["sd_backend_port"],
+		BackendUser:   legacy["sd_backend_username"],
+		BackendPass:   legacy["sd_backend_password"],
+		TemplateDir:   legacy["sd_template_dir"],
+	}
+}
+
+func deprecationWarnings(legacy LegacyConfig) []string {
+	var warnings []string
+	for _, key := range deprecatedLegacyKeys {
+		if v, ok := legacy[key]; ok && v != "" {
+			warnings = append(warnings, fmt.Sprintf("%q is deprecated and was not imported", key))
+		}
+	}
+	return warnings
+}
+
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Write marshals the AgentConfig as YAML and writes it to path. The file may
+// contain the Datadog API key, so it's written user-readable only.
+func (c *AgentConfig) Write(path string) error {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, 0600)
+}
@@ -9,24 +9,124 @@ package containerd
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// defaultSnapshotter is used to probe snapshotter health when the
+// containerd_snapshotter setting is empty.
+const defaultSnapshotter = "overlayfs"
+
+// defaultNamespace is used to probe snapshotter health when no namespace can
+// be listed (containerd's snapshot API is namespaced server-side).
+const defaultNamespace = "default"
+
 func init() {
-	diagnosis.Register("Containerd availability", diagnose)
+	diagnosis.Register("Containerd availability", diagnoseAvailability)
+	diagnosis.Register("Containerd namespaces", diagnoseNamespaces)
+	diagnosis.Register("Containerd tasks", diagnoseTasks)
+	diagnosis.Register("Containerd snapshotter", diagnoseSnapshotter)
 }
 
-// diagnose the Containerd socket connectivity
-func diagnose() error {
+func socketPath() string {
+	return config.Datadog.GetString("containerd_socket")
+}
+
+// diagnoseAvailability checks that the containerd socket is present and that
+// the daemon behind it actually responds to requests.
+func diagnoseAvailability() error {
 	ctx := context.Background()
 	cu, err := GetContainerdUtil()
 	if err != nil {
-		return err
+		return fmt.Errorf("socket %s: %s", socketPath(), err)
 	}
+
+	if err := cu.Ping(ctx); err != nil {
+		return fmt.Errorf("socket %s is present but containerd is not responding: %s", socketPath(), err)
+	}
+
 	ver, err := cu.Metadata(ctx)
-	if err == nil {
-		log.Infof("Connected to containerd - Version %s/%s", ver.Version, ver.Revision)
+	if err != nil {
+		return fmt.Errorf("socket %s: %s", socketPath(), err)
+	}
+	log.Infof("Connected to containerd via %s - Version %s/%s", socketPath(), ver.Version, ver.Revision)
+	return nil
+}
+
+// diagnoseNamespaces lists the containerd namespaces visible to the agent.
+func diagnoseNamespaces() error {
+	cu, err := GetContainerdUtil()
+	if err != nil {
+		return fmt.Errorf("socket %s: %s", socketPath(), err)
+	}
+
+	namespaces, err := cu.Namespaces(context.Background())
+	if err != nil {
+		return fmt.Errorf("socket %s: could not list namespaces: %s", socketPath(), err)
 	}
-	return err
-}
\ No newline at end of file
+
+	log.Infof("containerd socket %s: found %d namespace(s): %v", socketPath(), len(namespaces), namespaces)
+	return nil
+}
+
+// diagnoseTasks counts, per namespace, how many containers exist and how
+// many of them have a running task. It returns an error when a namespace's
+// containers cannot be listed; a failing TaskMetrics call only counts that
+// container as not running and is not reported as an error.
+func diagnoseTasks() error {
+	ctx := context.Background()
+	cu, err := GetContainerdUtil()
+	if err != nil {
+		return fmt.Errorf("socket %s: %s", socketPath(), err)
+	}
+
+	namespaces, err := cu.Namespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("socket %s: could not list namespaces: %s", socketPath(), err)
+	}
+
+	for _, ns := range namespaces {
+		containers, err := cu.Containers(ns)
+		if err != nil {
+			return fmt.Errorf("socket %s: namespace %q: could not list containers: %s", socketPath(), ns, err)
+		}
+
+		running := 0
+		for _, c := range containers {
+			if _, err := cu.TaskMetrics(ns, c.ID()); err == nil {
+				running++
+			}
+		}
+		log.Infof("containerd namespace %q: %d container(s), %d with a running task", ns, len(containers), running)
+	}
+	return nil
+}
+
+// diagnoseSnapshotter probes the configured snapshotter with a no-op Stat on
+// a key that is never expected to exist, so a misbehaving snapshotter is
+// reported on its own instead of surfacing as an opaque container failure.
+func diagnoseSnapshotter() error {
+	ctx := context.Background()
+	cu, err := GetContainerdUtil()
+	if err != nil {
+		return fmt.Errorf("socket %s: %s", socketPath(), err)
+	}
+
+	ns := defaultNamespace
+	if available, err := cu.Namespaces(ctx); err == nil && len(available) > 0 {
+		ns = available[0]
+	}
+
+	snapshotter := config.Datadog.GetString("containerd_snapshotter")
+	if snapshotter == "" {
+		snapshotter = defaultSnapshotter
+	}
+
+	if err := cu.ProbeSnapshotter(ctx, ns, snapshotter); err != nil {
+		return fmt.Errorf("socket %s: namespace %q: snapshotter %q probe failed: %s", socketPath(), ns, snapshotter, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build containerd
+
+package containerd
+
+import (
+	"context"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+)
+
+// pingTimeout bounds how long Ping waits for containerd to answer before
+// giving up.
+const pingTimeout = 5 * time.Second
+
+// probeSnapshotKey is never expected to exist; probing a snapshotter with it
+// lets ProbeSnapshotter tell "snapshotter reachable, key absent" (the
+// expected outcome) apart from "snapshotter unresponsive".
+const probeSnapshotKey = "datadog-agent-diagnose-probe"
+
+// Ping issues a short, non-streaming Version request bounded by its own
+// timeout, which lets callers distinguish "socket present but daemon
+// unresponsive" from "socket missing" - GetContainerdUtil already covers the
+// latter. A streaming call (e.g. subscribing to the events service) can't be
+// used for this: when the daemon is hung, the subscribe goroutine only
+// errors out *because* the context deadline fires, so racing it against
+// ctx.Done() on the same deadline always looks healthy.
+func (cu *containerdUtil) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	_, err := cu.client.Version(ctx)
+	return err
+}
+
+// ProbeSnapshotter does a no-op Stat against the given snapshotter, in the
+// given namespace, to verify it is actually serving requests. containerd's
+// snapshot API is namespaced server-side, so the caller must supply a
+// namespace that actually exists (e.g. the first one from Namespaces).
+func (cu *containerdUtil) ProbeSnapshotter(ctx context.Context, namespace, snapshotterName string) error {
+	ctx = namespaces.WithNamespace(ctx, namespace)
+	_, err := cu.client.SnapshotService(snapshotterName).Stat(ctx, probeSnapshotKey)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// Package system collects small host-level helpers (uptime, and friends)
+// that several subsystems - checks, host metadata, flare - need and would
+// otherwise each reimplement per platform.
+package system
@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build darwin freebsd
+
+package system
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Uptime returns how long the host has been running, computed from the
+// kern.boottime sysctl.
+func Uptime() (time.Duration, error) {
+	tv, err := unix.SysctlTimeval("kern.boottime")
+	if err != nil {
+		return 0, err
+	}
+
+	boot := time.Unix(tv.Sec, int64(tv.Usec)*int64(time.Microsecond))
+	return time.Since(boot), nil
+}
@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018 Datadog, Inc.
+
+// +build windows
+
+package system
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel32        = windows.NewLazyDLL("kernel32.dll")
+	procGetTickCount64 = modkernel32.NewProc("GetTickCount64")
+)
+
+// Uptime returns how long the host has been running, read from
+// kernel32!GetTickCount64.
+func Uptime() (time.Duration, error) {
+	r, _, err := procGetTickCount64.Call()
+	if r == 0 {
+		return 0, err
+	}
+	return time.Duration(r) * time.Millisecond, nil
+}